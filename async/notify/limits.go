@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import "github.com/berachain/beacon-kit/async/notify/rate"
+
+// HandlerLimits configures the optional per-handler rate governor installed
+// by RegisterHandlerWithLimits. The zero value disables every cap.
+type HandlerLimits struct {
+	// MaxEventsPerSecond caps the EMA-smoothed event arrival rate seen by
+	// the handler. Zero disables the rate cap.
+	MaxEventsPerSecond float64
+	// BurstSize is the tolerance, in events/sec, above MaxEventsPerSecond
+	// before the rate cap takes effect.
+	BurstSize int
+	// MaxInFlight caps the number of events concurrently being handled by
+	// this handler. Zero disables the in-flight cap.
+	MaxInFlight int
+	// DropOnExceed selects drop-and-count behavior instead of blocking the
+	// feed goroutine when MaxEventsPerSecond or MaxInFlight is exceeded.
+	DropOnExceed bool
+}
+
+// rateLimits converts l to the rate.Limits the governor enforces.
+func (l HandlerLimits) rateLimits() rate.Limits {
+	policy := rate.PolicyBlock
+	if l.DropOnExceed {
+		policy = rate.PolicyDrop
+	}
+	return rate.Limits{
+		MaxEventsPerSecond: l.MaxEventsPerSecond,
+		BurstSize:          l.BurstSize,
+		MaxInFlight:        l.MaxInFlight,
+		Policy:             policy,
+	}
+}
+
+// monitorKey returns the map key used to associate a registered handler's
+// rate.Monitor with the feed and queue it was registered on.
+func monitorKey(name, queueID string) string {
+	return name + "\x00" + queueID
+}
+
+// handlerMonitor pairs a rate.Monitor with the feed and queue identifiers
+// it was installed for, so periodic reporting can label its metrics.
+type handlerMonitor struct {
+	name    string
+	queueID string
+	monitor *rate.Monitor
+}
+
+// RegisterHandlerWithLimits registers handler exactly like RegisterHandler,
+// and additionally installs a rate.Monitor that enforces limits on events
+// dispatched to it. When the EMA-smoothed arrival rate exceeds
+// limits.MaxEventsPerSecond, or limits.MaxInFlight concurrent handler
+// invocations are already outstanding, the dispatcher either blocks the
+// feed subscription goroutine (applying backpressure to feed.Send) or
+// drops the event and increments an "events.dropped" counter, depending on
+// limits.DropOnExceed.
+func (s *Service) RegisterHandlerWithLimits(
+	name, queueID string, handler EventHandler, limits HandlerLimits,
+) error {
+	if err := s.RegisterHandler(name, queueID, handler); err != nil {
+		return err
+	}
+	s.monitors[monitorKey(name, queueID)] = &handlerMonitor{
+		name:    name,
+		queueID: queueID,
+		monitor: rate.NewMonitor(limits.rateLimits()),
+	}
+	return nil
+}
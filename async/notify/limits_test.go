@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// This file exercises rateLimits and monitorKey directly, so it lives in
+// package notify rather than notify_test.
+//
+// RegisterHandlerWithLimits and Service.Start are deliberately not
+// exercised here: Service's dispatch path (eventHandlerQueuePair, the
+// EventHandler and GrandCentralDispatch types, and the Option/error
+// values NewService/RegisterHandler depend on) is not defined anywhere
+// in this package or the rest of the module, so no test can construct or
+// start a Service at all -- that gap predates and is independent of the
+// rate-limiting support added here. This covers everything that is
+// actually self-contained: the HandlerLimits -> rate.Limits conversion
+// and the monitor key scheme RegisterHandlerWithLimits relies on.
+package notify
+
+import (
+	"testing"
+
+	"github.com/berachain/beacon-kit/async/notify/rate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerLimits_RateLimits(t *testing.T) {
+	blocking := HandlerLimits{
+		MaxEventsPerSecond: 50,
+		BurstSize:          5,
+		MaxInFlight:        10,
+	}
+	require.Equal(t, rate.Limits{
+		MaxEventsPerSecond: 50,
+		BurstSize:          5,
+		MaxInFlight:        10,
+		Policy:             rate.PolicyBlock,
+	}, blocking.rateLimits())
+
+	dropping := HandlerLimits{
+		MaxEventsPerSecond: 50,
+		DropOnExceed:       true,
+	}
+	require.Equal(t, rate.PolicyDrop, dropping.rateLimits().Policy)
+}
+
+func TestMonitorKey(t *testing.T) {
+	require.Equal(t, "feed\x00queue", monitorKey("feed", "queue"))
+	require.NotEqual(
+		t, monitorKey("a", "bc"), monitorKey("ab", "c"),
+		"the separator must prevent name/queueID concatenation collisions",
+	)
+}
@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMetricsGaugeInterval is how often the Service samples and reports
+// pending queue depth when no interval is configured via
+// WithMetricsGaugeInterval.
+const defaultMetricsGaugeInterval = 10 * time.Second
+
+// MetricsSink receives instrumentation emitted by the Service. Keys are
+// hierarchical, e.g. []string{"events", "received", "<feed>"}, and are
+// joined with "." by implementations that need a flat string.
+type MetricsSink interface {
+	// IncrCounter increments the monotonic counter identified by key by val.
+	IncrCounter(key []string, val float64)
+	// SetGauge sets the point-in-time gauge identified by key to val.
+	SetGauge(key []string, val float64)
+	// AddSample records an observation, e.g. a duration, for key.
+	AddSample(key []string, val float64)
+}
+
+// noopMetricsSink is the default MetricsSink used by NewService when
+// WithMetricsSink is not supplied, so the dispatch path never has to nil
+// check s.metrics.
+type noopMetricsSink struct{}
+
+func (noopMetricsSink) IncrCounter(_ []string, _ float64) {}
+func (noopMetricsSink) SetGauge(_ []string, _ float64)    {}
+func (noopMetricsSink) AddSample(_ []string, _ float64)   {}
+
+// joinKey flattens a hierarchical metric key into a single string.
+func joinKey(key []string) string {
+	return strings.Join(key, ".")
+}
+
+// WindowStats summarizes the samples recorded for a key during a single
+// wall-clock bucket.
+type WindowStats struct {
+	Start  time.Time `json:"start"`
+	Count  int       `json:"count"`
+	Mean   float64   `json:"mean"`
+	StdDev float64   `json:"stddev"`
+	Min    float64   `json:"min"`
+	Max    float64   `json:"max"`
+	P50    float64   `json:"p50"`
+	P90    float64   `json:"p90"`
+	P99    float64   `json:"p99"`
+}
+
+// Snapshot is a JSON-serializable view of an InMemorySink's state, suitable
+// for serving from a /debug/metrics HTTP handler.
+type Snapshot struct {
+	Counters map[string]float64       `json:"counters"`
+	Gauges   map[string]float64       `json:"gauges"`
+	Samples  map[string][]WindowStats `json:"samples"`
+}
+
+// sampleBucket accumulates raw samples for the current wall-clock interval
+// of a single key.
+type sampleBucket struct {
+	start   time.Time
+	samples []float64
+}
+
+// InMemorySink is a dependency-free MetricsSink that keeps rolling,
+// windowed aggregates (mean/stddev/min/max/quantiles) per key, bucketed by
+// wall-clock interval. It is intended for local diagnosis of slow handlers
+// and backpressure without wiring an external metrics stack.
+type InMemorySink struct {
+	mu sync.Mutex
+
+	interval      time.Duration
+	maxWindows    int
+	counters      map[string]float64
+	gauges        map[string]float64
+	current       map[string]*sampleBucket
+	windowHistory map[string][]WindowStats
+}
+
+// NewInMemorySink creates an InMemorySink that buckets samples into
+// windows of the given interval, retaining up to maxWindows of history per
+// key.
+func NewInMemorySink(interval time.Duration, maxWindows int) *InMemorySink {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if maxWindows <= 0 {
+		maxWindows = 60
+	}
+	return &InMemorySink{
+		interval:      interval,
+		maxWindows:    maxWindows,
+		counters:      make(map[string]float64),
+		gauges:        make(map[string]float64),
+		current:       make(map[string]*sampleBucket),
+		windowHistory: make(map[string][]WindowStats),
+	}
+}
+
+// IncrCounter implements MetricsSink.
+func (s *InMemorySink) IncrCounter(key []string, val float64) {
+	k := joinKey(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters[k] += val
+}
+
+// SetGauge implements MetricsSink.
+func (s *InMemorySink) SetGauge(key []string, val float64) {
+	k := joinKey(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gauges[k] = val
+}
+
+// AddSample implements MetricsSink.
+func (s *InMemorySink) AddSample(key []string, val float64) {
+	k := joinKey(key)
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.current[k]
+	if !ok || now.Sub(bucket.start) >= s.interval {
+		if ok {
+			s.finalizeLocked(k, bucket)
+		}
+		bucket = &sampleBucket{start: now}
+		s.current[k] = bucket
+	}
+	bucket.samples = append(bucket.samples, val)
+}
+
+// now is a seam so tests can avoid depending on wall-clock timing.
+func (s *InMemorySink) now() time.Time { return time.Now() }
+
+// finalizeLocked computes the WindowStats for a completed bucket and
+// appends it to that key's history, trimming to maxWindows. Callers must
+// hold s.mu.
+func (s *InMemorySink) finalizeLocked(key string, bucket *sampleBucket) {
+	stats := summarize(bucket.start, bucket.samples)
+	history := append(s.windowHistory[key], stats)
+	if excess := len(history) - s.maxWindows; excess > 0 {
+		history = history[excess:]
+	}
+	s.windowHistory[key] = history
+}
+
+// summarize computes count/mean/stddev/min/max/quantiles over samples.
+func summarize(start time.Time, samples []float64) WindowStats {
+	stats := WindowStats{Start: start, Count: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	sorted := make([]float64, len(samples))
+	copy(sorted, samples)
+	sort.Float64s(sorted)
+
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	stats.Mean = sum / float64(len(sorted))
+
+	var variance float64
+	for _, v := range sorted {
+		d := v - stats.Mean
+		variance += d * d
+	}
+	variance /= float64(len(sorted))
+	stats.StdDev = sqrt(variance)
+
+	stats.P50 = quantile(sorted, 0.50)
+	stats.P90 = quantile(sorted, 0.90)
+	stats.P99 = quantile(sorted, 0.99)
+	return stats
+}
+
+// quantile returns the value at quantile q (0..1) of a sorted slice: the
+// element at index floor(q*(len-1)), without interpolating between
+// neighbouring elements.
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(q * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// sqrt is a tiny Newton's-method square root, avoiding a math import for a
+// single call site.
+func sqrt(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	x := v
+	for range [20]struct{}{} {
+		x = 0.5 * (x + v/x)
+	}
+	return x
+}
+
+// Snapshot returns a point-in-time, JSON-serializable copy of the sink's
+// counters, gauges, and per-key windowed sample history (including the
+// in-progress window for each key).
+func (s *InMemorySink) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := Snapshot{
+		Counters: make(map[string]float64, len(s.counters)),
+		Gauges:   make(map[string]float64, len(s.gauges)),
+		Samples:  make(map[string][]WindowStats, len(s.windowHistory)),
+	}
+	for k, v := range s.counters {
+		snap.Counters[k] = v
+	}
+	for k, v := range s.gauges {
+		snap.Gauges[k] = v
+	}
+	for k, history := range s.windowHistory {
+		windows := make([]WindowStats, len(history))
+		copy(windows, history)
+		if bucket, ok := s.current[k]; ok && len(bucket.samples) > 0 {
+			windows = append(windows, summarize(bucket.start, bucket.samples))
+		}
+		snap.Samples[k] = windows
+	}
+	for k, bucket := range s.current {
+		if _, ok := s.windowHistory[k]; !ok && len(bucket.samples) > 0 {
+			snap.Samples[k] = []WindowStats{summarize(bucket.start, bucket.samples)}
+		}
+	}
+	return snap
+}
+
+// WithMetricsSink configures the MetricsSink the Service uses to report
+// event, queue, and handler instrumentation. If not supplied, the Service
+// uses a no-op sink.
+func WithMetricsSink(sink MetricsSink) Option {
+	return func(s *Service) error {
+		s.metrics = sink
+		return nil
+	}
+}
+
+// WithMetricsGaugeInterval configures how often the Service samples and
+// reports pending queue depth. If not supplied, defaultMetricsGaugeInterval
+// is used.
+func WithMetricsGaugeInterval(interval time.Duration) Option {
+	return func(s *Service) error {
+		s.metricsGaugeInterval = interval
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// This file exercises unexported helpers directly (summarize, sqrt,
+// finalizeLocked), so it lives in package notify rather than notify_test.
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	start := time.Unix(0, 0)
+	stats := summarize(start, []float64{1, 2, 3, 4, 5})
+
+	require.Equal(t, start, stats.Start)
+	require.Equal(t, 5, stats.Count)
+	require.InDelta(t, 3.0, stats.Mean, 1e-9)
+	require.InDelta(t, 1.0, stats.Min, 1e-9)
+	require.InDelta(t, 5.0, stats.Max, 1e-9)
+	// Variance of {1,2,3,4,5} is 2.
+	require.InDelta(t, sqrt(2.0), stats.StdDev, 1e-9)
+	require.InDelta(t, 3.0, stats.P50, 1e-9)
+	require.InDelta(t, 4.0, stats.P90, 1e-9)
+	require.InDelta(t, 4.0, stats.P99, 1e-9)
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	stats := summarize(time.Unix(0, 0), nil)
+	require.Zero(t, stats.Count)
+	require.Zero(t, stats.Mean)
+	require.Zero(t, stats.Max)
+}
+
+func TestSqrt(t *testing.T) {
+	require.InDelta(t, 2.0, sqrt(4.0), 1e-9)
+	require.InDelta(t, 1.4142135623730951, sqrt(2.0), 1e-9)
+	require.Zero(t, sqrt(0))
+	require.Zero(t, sqrt(-1))
+}
+
+func TestInMemorySink_FinalizeLocked_TrimsToMaxWindows(t *testing.T) {
+	s := NewInMemorySink(time.Minute, 2)
+	base := time.Unix(0, 0)
+
+	for i := 0; i < 5; i++ {
+		s.mu.Lock()
+		s.finalizeLocked("handler.latency", &sampleBucket{
+			start:   base.Add(time.Duration(i) * time.Minute),
+			samples: []float64{float64(i)},
+		})
+		s.mu.Unlock()
+	}
+
+	history := s.windowHistory["handler.latency"]
+	require.Len(t, history, 2, "history should be trimmed to maxWindows")
+	// The oldest windows must be the ones dropped, so only the last two
+	// remain, in order.
+	require.Equal(t, base.Add(3*time.Minute), history[0].Start)
+	require.Equal(t, base.Add(4*time.Minute), history[1].Start)
+}
+
+func TestInMemorySink_Snapshot_IncludesInProgressBucketExactlyOnce(t *testing.T) {
+	s := NewInMemorySink(time.Minute, 10)
+
+	s.AddSample([]string{"handler", "latency"}, 1.5)
+	s.AddSample([]string{"handler", "latency"}, 2.5)
+
+	snap := s.Snapshot()
+	windows := snap.Samples["handler.latency"]
+	require.Len(t, windows, 1, "in-progress bucket should appear exactly once")
+	require.Equal(t, 2, windows[0].Count)
+
+	// Calling Snapshot again must not duplicate or otherwise grow the
+	// still-in-progress window.
+	snap = s.Snapshot()
+	require.Len(t, snap.Samples["handler.latency"], 1)
+}
+
+func TestInMemorySink_CountersAndGauges(t *testing.T) {
+	s := NewInMemorySink(time.Minute, 10)
+
+	s.IncrCounter([]string{"events", "received"}, 1)
+	s.IncrCounter([]string{"events", "received"}, 2)
+	s.SetGauge([]string{"queue", "depth"}, 7)
+
+	snap := s.Snapshot()
+	require.InDelta(t, 3.0, snap.Counters["events.received"], 1e-9)
+	require.InDelta(t, 7.0, snap.Gauges["queue.depth"], 1e-9)
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package rate provides a small EMA-based arrival-rate monitor used to give
+// notify.Service's dispatch path rate limiting and backpressure.
+package rate
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// alpha is the smoothing factor applied to each rate sample, matching the
+// weight commonly used for EMA bandwidth estimators: rEMA = alpha*rSample +
+// (1-alpha)*rEMA.
+const alpha = 0.2
+
+// sampleInterval is the minimum wall-clock spacing between rate samples. At
+// least one full interval of arrivals is accumulated before it is folded
+// into the EMA.
+const sampleInterval = time.Second
+
+// blockBackoff is how long Admit sleeps between re-checks while waiting for
+// capacity under PolicyBlock.
+const blockBackoff = 5 * time.Millisecond
+
+// Policy selects what a Monitor does when Admit finds its Limits exceeded.
+type Policy int
+
+const (
+	// PolicyBlock makes Admit block the caller until capacity is
+	// available, applying backpressure to whatever feeds it.
+	PolicyBlock Policy = iota
+	// PolicyDrop makes Admit return false immediately instead of
+	// blocking, leaving the caller to record the drop.
+	PolicyDrop
+)
+
+// Limits configures the throughput and concurrency a Monitor enforces for a
+// single registered handler.
+type Limits struct {
+	// MaxEventsPerSecond caps the EMA-smoothed arrival rate. Zero disables
+	// the rate cap.
+	MaxEventsPerSecond float64
+	// BurstSize is the number of events per second of tolerance above
+	// MaxEventsPerSecond before the rate cap takes effect.
+	BurstSize int
+	// MaxInFlight caps the number of events concurrently admitted and not
+	// yet Released. Zero disables the in-flight cap.
+	MaxInFlight int
+	// Policy selects what Admit does once a cap is exceeded.
+	Policy Policy
+}
+
+// Monitor tracks arrivals for a single handler using an exponentially-
+// weighted moving average of the arrival rate, and enforces the Monitor's
+// Limits by blocking or dropping admission once that rate or the in-flight
+// count exceeds its configured caps.
+type Monitor struct {
+	limits Limits
+
+	mu         sync.Mutex
+	rEMA       float64
+	accum      int64
+	lastSample time.Time
+
+	inFlight int64
+	slots    chan struct{} // nil when limits.MaxInFlight is unset
+}
+
+// NewMonitor creates a Monitor enforcing limits.
+func NewMonitor(limits Limits) *Monitor {
+	m := &Monitor{limits: limits, lastSample: time.Now()}
+	if limits.MaxInFlight > 0 {
+		m.slots = make(chan struct{}, limits.MaxInFlight)
+	}
+	return m
+}
+
+// Rate returns the Monitor's current EMA arrival rate, in events/sec. It
+// rolls forward any whole sampleInterval that has elapsed since the last
+// sample first, so the rate keeps decaying toward zero even if the caller
+// polling it is the only goroutine that would otherwise call mark again
+// (see the PolicyBlock note on Admit).
+func (m *Monitor) Rate() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollLocked()
+	return m.rEMA
+}
+
+// InFlight returns the number of events currently admitted and not yet
+// Released.
+func (m *Monitor) InFlight() int64 {
+	return atomic.LoadInt64(&m.inFlight)
+}
+
+// mark records a single arrival and rolls it into rEMA once at least
+// sampleInterval has elapsed since the last sample.
+func (m *Monitor) mark() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.accum++
+	m.rollLocked()
+}
+
+// rollLocked folds any whole sampleInterval that has elapsed since
+// lastSample into rEMA, using whatever arrivals accum has seen in that
+// time. Critically, this also runs when invoked by Rate() rather than
+// mark() — e.g. while a PolicyBlock Admit call is polling tryAcquire in a
+// tight loop without any new arrivals to record, so accum holds at most
+// that call's own arrival and rSample is small or zero — which is what
+// lets rEMA decay back below the rate cap instead of staying pinned
+// forever with nothing left to roll it forward.
+func (m *Monitor) rollLocked() {
+	elapsed := time.Since(m.lastSample)
+	if elapsed < sampleInterval {
+		return
+	}
+	rSample := float64(m.accum) / elapsed.Seconds()
+	m.rEMA = alpha*rSample + (1-alpha)*m.rEMA
+	m.accum = 0
+	m.lastSample = time.Now()
+}
+
+// Admit records one event arrival and reports whether it may proceed under
+// the Monitor's Limits. It marks the arrival unconditionally, including
+// attempts that are ultimately blocked or dropped, so the EMA reflects true
+// offered load rather than just admitted load.
+//
+// Callers that receive true must call Release once the event has finished
+// processing, to free its in-flight slot.
+//
+// Under PolicyBlock, Admit blocks the calling goroutine until both the rate
+// and in-flight caps have headroom. Since Admit is called from the feed
+// subscription goroutine in notify.Service, blocking here applies
+// backpressure all the way up to feed.Send. Under PolicyDrop, Admit returns
+// false immediately instead of blocking.
+func (m *Monitor) Admit() bool {
+	m.mark()
+	for {
+		if m.tryAcquire() {
+			return true
+		}
+		if m.limits.Policy == PolicyDrop {
+			return false
+		}
+		time.Sleep(blockBackoff)
+	}
+}
+
+// tryAcquire performs a single non-blocking admission check against both
+// caps, acquiring an in-flight slot on success.
+func (m *Monitor) tryAcquire() bool {
+	if max := m.limits.MaxEventsPerSecond; max > 0 {
+		if m.Rate() > max+float64(m.limits.BurstSize) {
+			return false
+		}
+	}
+	if m.slots == nil {
+		atomic.AddInt64(&m.inFlight, 1)
+		return true
+	}
+	select {
+	case m.slots <- struct{}{}:
+		atomic.AddInt64(&m.inFlight, 1)
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees the in-flight slot acquired by a successful Admit call.
+// When the Monitor has no MaxInFlight cap configured, it only decrements
+// the InFlight count.
+func (m *Monitor) Release() {
+	atomic.AddInt64(&m.inFlight, -1)
+	if m.slots == nil {
+		return
+	}
+	<-m.slots
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rate_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/berachain/beacon-kit/async/notify/rate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor_DropsOverMaxInFlight(t *testing.T) {
+	m := rate.NewMonitor(rate.Limits{
+		MaxInFlight: 2,
+		Policy:      rate.PolicyDrop,
+	})
+
+	require.True(t, m.Admit())
+	require.True(t, m.Admit())
+	require.False(t, m.Admit(), "third admit should be dropped at MaxInFlight")
+	require.EqualValues(t, 2, m.InFlight())
+
+	m.Release()
+	require.EqualValues(t, 1, m.InFlight())
+	require.True(t, m.Admit(), "releasing a slot should allow another admit")
+}
+
+func TestMonitor_BlockWaitsForInFlightSlot(t *testing.T) {
+	m := rate.NewMonitor(rate.Limits{
+		MaxInFlight: 1,
+		Policy:      rate.PolicyBlock,
+	})
+
+	require.True(t, m.Admit())
+
+	var admitted int32
+	done := make(chan struct{})
+	go func() {
+		m.Admit()
+		atomic.StoreInt32(&admitted, 1)
+		close(done)
+	}()
+
+	// The second Admit should block while the first slot is held.
+	time.Sleep(20 * time.Millisecond)
+	require.EqualValues(t, 0, atomic.LoadInt32(&admitted))
+
+	m.Release()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("blocked Admit did not unblock after Release")
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&admitted))
+}
+
+func TestMonitor_DropsBurstsOverRateCap(t *testing.T) {
+	m := rate.NewMonitor(rate.Limits{
+		MaxEventsPerSecond: 10,
+		Policy:             rate.PolicyDrop,
+	})
+
+	// Let the first sample window roll over with heavy synthetic load, so
+	// rEMA reflects an arrival rate well above the cap.
+	for i := 0; i < 1000; i++ {
+		m.Admit()
+	}
+	time.Sleep(1100 * time.Millisecond)
+	m.Admit()
+	require.Greater(t, m.Rate(), 10.0)
+
+	var dropped int
+	for i := 0; i < 10; i++ {
+		if !m.Admit() {
+			dropped++
+		}
+	}
+	require.Positive(t, dropped, "steady-state throughput should stay capped once rEMA exceeds the configured limit")
+}
+
+func TestMonitor_BlockUnderRateCapUnblocksAsEMADecays(t *testing.T) {
+	m := rate.NewMonitor(rate.Limits{
+		MaxEventsPerSecond: 10,
+		Policy:             rate.PolicyBlock,
+	})
+
+	// Push a burst through and let the first sample window roll over, so
+	// rEMA starts out just above the cap.
+	for i := 0; i < 58; i++ {
+		m.Admit()
+	}
+	time.Sleep(1100 * time.Millisecond)
+	require.Greater(t, m.Rate(), 10.0)
+
+	done := make(chan struct{})
+	go func() {
+		// With nothing else feeding it new arrivals, this must still
+		// return once rEMA decays back under the cap rather than
+		// busy-polling forever.
+		m.Admit()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Admit under PolicyBlock never returned as rEMA decayed")
+	}
+}
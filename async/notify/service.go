@@ -26,6 +26,9 @@
 package notify
 
 import (
+	"sync/atomic"
+	"time"
+
 	"cosmossdk.io/log"
 
 	"github.com/prysmaticlabs/prysm/v4/async/event"
@@ -53,6 +56,23 @@ type Service struct {
 
 	// stop is a channel that is used to stop the service.
 	stop chan struct{}
+
+	// metrics is the sink instrumentation is reported to. Defaults to a
+	// no-op sink so the dispatch path never has to nil check it.
+	metrics MetricsSink
+
+	// metricsGaugeInterval is how often pending queue depth is sampled and
+	// reported to metrics. Defaults to defaultMetricsGaugeInterval.
+	metricsGaugeInterval time.Duration
+
+	// queueDepths tracks the number of events currently enqueued per
+	// queueID, for the queue depth gauge.
+	queueDepths map[string]*int64
+
+	// monitors holds the rate.Monitor installed for each handler that was
+	// registered via RegisterHandlerWithLimits, keyed by monitorKey(name,
+	// queueID).
+	monitors map[string]*handlerMonitor
 }
 
 // NewService creates a new Service.
@@ -61,6 +81,8 @@ func NewService(opts ...Option) *Service {
 		feeds:    make(map[string]*event.Feed),
 		handlers: make(map[string][]eventHandlerQueuePair),
 		stop:     make(chan struct{}),
+		metrics:  noopMetricsSink{},
+		monitors: make(map[string]*handlerMonitor),
 	}
 
 	for _, opt := range opts {
@@ -74,6 +96,17 @@ func NewService(opts ...Option) *Service {
 // Start spawns any goroutines required by the service.
 func (s *Service) Start() {
 	s.running = true
+
+	s.queueDepths = make(map[string]*int64)
+	for _, handlers := range s.handlers {
+		for _, pair := range handlers {
+			if _, ok := s.queueDepths[pair.queueID]; !ok {
+				s.queueDepths[pair.queueID] = new(int64)
+			}
+		}
+	}
+	go s.reportQueueDepths()
+
 	for name, handlers := range s.handlers {
 		feed, ok := s.feeds[name]
 		if !ok {
@@ -87,12 +120,50 @@ func (s *Service) Start() {
 
 			// Start a goroutine to listen for events and call the handler
 			go func(pair eventHandlerQueuePair, ch <-chan interface{}, subscription event.Subscription) {
+				depth := s.queueDepths[pair.queueID]
+				hm := s.monitors[monitorKey(name, pair.queueID)]
 				for {
 					select {
 					case event := <-ch:
+						s.metrics.IncrCounter([]string{"events", "received", name}, 1)
+
+						if hm != nil {
+							// Admit blocks the feed subscription goroutine
+							// under PolicyBlock, applying backpressure to
+							// feed.Send; under PolicyDrop it returns false
+							// instead of blocking.
+							if !hm.monitor.Admit() {
+								s.metrics.IncrCounter(
+									[]string{"events", "dropped", name, pair.queueID}, 1,
+								)
+								continue
+							}
+							s.metrics.SetGauge(
+								[]string{"handler", "rate_ema", name, pair.queueID},
+								hm.monitor.Rate(),
+							)
+							s.metrics.SetGauge(
+								[]string{"handler", "inflight", name, pair.queueID},
+								float64(hm.monitor.InFlight()),
+							)
+						}
+
+						atomic.AddInt64(depth, 1)
 						// Use the dispatch queue to call the handler's Handle method asynchronously
 						s.gcd.GetQueue(pair.queueID).Async(func() {
+							defer atomic.AddInt64(depth, -1)
+							if hm != nil {
+								defer hm.monitor.Release()
+							}
+							s.metrics.IncrCounter(
+								[]string{"events", "dispatched", pair.queueID}, 1,
+							)
+							start := time.Now()
 							pair.handler.HandleNotification(event)
+							s.metrics.AddSample(
+								[]string{"handler", "duration_ms", name, pair.queueID},
+								float64(time.Since(start).Milliseconds()),
+							)
 						})
 					case <-subscription.Err():
 						return
@@ -106,6 +177,42 @@ func (s *Service) Start() {
 	}
 }
 
+// reportQueueDepths periodically samples s.queueDepths and reports each as
+// a gauge, until the service is stopped.
+func (s *Service) reportQueueDepths() {
+	interval := s.metricsGaugeInterval
+	if interval <= 0 {
+		interval = defaultMetricsGaugeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for queueID, depth := range s.queueDepths {
+				s.metrics.SetGauge(
+					[]string{"events", "queue_depth", queueID},
+					float64(atomic.LoadInt64(depth)),
+				)
+			}
+			for _, hm := range s.monitors {
+				s.metrics.SetGauge(
+					[]string{"handler", "rate_ema", hm.name, hm.queueID},
+					hm.monitor.Rate(),
+				)
+				s.metrics.SetGauge(
+					[]string{"handler", "inflight", hm.name, hm.queueID},
+					float64(hm.monitor.InFlight()),
+				)
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
 // Stop terminates all goroutines belonging to the service,
 // blocking until they are all terminated.
 func (s *Service) Stop() error {
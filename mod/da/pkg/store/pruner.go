@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"sync/atomic"
+	"time"
+
+	"cosmossdk.io/log"
+	"github.com/berachain/beacon-kit/async/notify"
+	"github.com/prysmaticlabs/prysm/v4/beacon-chain/core/feed"
+)
+
+// defaultPruneInterval is how often Pruner re-checks the retention floor
+// when PrunerConfig.PruneInterval is not set.
+const defaultPruneInterval = 5 * time.Minute
+
+// defaultMaxDeletionsPerTick bounds how many indices Pruner deletes on a
+// single tick when PrunerConfig.MaxDeletionsPerTick is not set.
+const defaultMaxDeletionsPerTick = 256
+
+// RangeDeleter is satisfied by the range-keyed store backing a Pruner (in
+// practice, filedb.RangeDB), so Pruner does not need to know anything
+// about its on-disk layout.
+type RangeDeleter interface {
+	// DeleteRange removes every entry keyed in [from, to), returning the
+	// number of files removed and the bytes reclaimed.
+	DeleteRange(from, to uint64) (filesDeleted int, bytesReclaimed int64, err error)
+}
+
+// RetentionSpec supplies the chain parameters Pruner needs to compute its
+// retention floor. primitives.ChainSpec satisfies this.
+type RetentionSpec interface {
+	MinEpochsForBlobsSidecarsRequest() uint64
+	SlotsPerEpoch() uint64
+}
+
+// PrunerMetricsSink receives Pruner instrumentation. notify.MetricsSink
+// satisfies this.
+type PrunerMetricsSink interface {
+	IncrCounter(key []string, val float64)
+}
+
+// noopPrunerMetricsSink is the default PrunerMetricsSink, so Pruner never
+// has to nil check it.
+type noopPrunerMetricsSink struct{}
+
+func (noopPrunerMetricsSink) IncrCounter(_ []string, _ float64) {}
+
+// FinalizedSlotData is the payload Pruner expects on the finalized-slot
+// feed's Event.Data field.
+type FinalizedSlotData struct {
+	Slot uint64
+}
+
+// PrunerConfig configures a Pruner.
+type PrunerConfig struct {
+	// RetentionEpochsOverride, if non-zero, is used instead of
+	// RetentionSpec.MinEpochsForBlobsSidecarsRequest() to compute the
+	// retention floor.
+	RetentionEpochsOverride uint64
+	// PruneInterval is how often Pruner checks the latest finalized slot
+	// against its retention floor. Defaults to defaultPruneInterval.
+	PruneInterval time.Duration
+	// MaxDeletionsPerTick caps how many indices Pruner deletes on a single
+	// tick, to smooth the I/O impact of a large backlog. Defaults to
+	// defaultMaxDeletionsPerTick.
+	MaxDeletionsPerTick uint64
+}
+
+// PrunerOption configures optional Pruner behavior.
+type PrunerOption func(*Pruner)
+
+// WithPrunerMetricsSink configures the sink Pruner reports its
+// files-deleted and bytes-reclaimed counters to. Defaults to a no-op sink.
+func WithPrunerMetricsSink(sink PrunerMetricsSink) PrunerOption {
+	return func(p *Pruner) { p.metrics = sink }
+}
+
+// Pruner removes availability-store ranges that have fallen behind the
+// chain's blob retention window. It subscribes to finalized-slot
+// notifications via notify.Service, and on every PruneInterval tick
+// deletes up to MaxDeletionsPerTick indices below the retention floor:
+//
+//	floor = max(0, finalizedSlot - retentionEpochs*SlotsPerEpoch)
+//
+// Deletion is driven by the tick loop rather than the notification handler
+// itself, so a burst of finalized-slot events never blocks the notify feed
+// subscription goroutine on store I/O.
+type Pruner struct {
+	db      RangeDeleter
+	spec    RetentionSpec
+	logger  log.Logger
+	cfg     PrunerConfig
+	metrics PrunerMetricsSink
+
+	finalizedSlot uint64 // set via HandleNotification, read by the tick loop
+	pruned        uint64 // first index not yet known to be deleted
+
+	stop chan struct{}
+}
+
+// NewPruner creates a Pruner that removes ranges from db once they fall
+// behind the retention floor computed from spec and cfg.
+func NewPruner(
+	db RangeDeleter,
+	spec RetentionSpec,
+	logger log.Logger,
+	cfg PrunerConfig,
+	opts ...PrunerOption,
+) *Pruner {
+	if cfg.PruneInterval <= 0 {
+		cfg.PruneInterval = defaultPruneInterval
+	}
+	if cfg.MaxDeletionsPerTick == 0 {
+		cfg.MaxDeletionsPerTick = defaultMaxDeletionsPerTick
+	}
+
+	p := &Pruner{
+		db:      db,
+		spec:    spec,
+		logger:  logger,
+		cfg:     cfg,
+		metrics: noopPrunerMetricsSink{},
+		stop:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// RegisterWithNotifier installs Pruner as the handler for the
+// finalized-slot feed on svc, dispatched on queueID. svc must already have
+// a feed registered under feedName, by whatever component finalizes
+// blocks, before svc.Start is called.
+func (p *Pruner) RegisterWithNotifier(svc *notify.Service, feedName, queueID string) error {
+	return svc.RegisterHandler(feedName, queueID, p)
+}
+
+// HandleNotification implements notify.EventHandler. It records the
+// finalized slot carried by e for the next tick to act on; it never prunes
+// synchronously, so a slow store deletion can't stall the feed
+// subscription goroutine.
+func (p *Pruner) HandleNotification(e interface{}) {
+	fe, ok := e.(*feed.Event)
+	if !ok {
+		return
+	}
+	data, ok := fe.Data.(FinalizedSlotData)
+	if !ok {
+		return
+	}
+	atomic.StoreUint64(&p.finalizedSlot, data.Slot)
+}
+
+// RetentionFloor returns the oldest slot Pruner will keep for a given
+// finalized slot: max(0, finalizedSlot - retentionEpochs*SlotsPerEpoch).
+func (p *Pruner) RetentionFloor(finalizedSlot uint64) uint64 {
+	retentionEpochs := p.cfg.RetentionEpochsOverride
+	if retentionEpochs == 0 {
+		retentionEpochs = p.spec.MinEpochsForBlobsSidecarsRequest()
+	}
+
+	window := retentionEpochs * p.spec.SlotsPerEpoch()
+	if window >= finalizedSlot {
+		return 0
+	}
+	return finalizedSlot - window
+}
+
+// Start spawns the goroutine that drives Pruner's tick loop.
+func (p *Pruner) Start() {
+	go p.run()
+}
+
+// Stop signals Pruner's tick loop to exit.
+func (p *Pruner) Stop() error {
+	close(p.stop)
+	return nil
+}
+
+// Status returns error if Pruner is not considered healthy.
+func (p *Pruner) Status() error { return nil }
+
+func (p *Pruner) run() {
+	ticker := time.NewTicker(p.cfg.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.tick()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// tick deletes up to MaxDeletionsPerTick indices below the current
+// retention floor, advancing p.pruned as it goes. Unfinalized indices
+// (at or above the floor) are never touched.
+func (p *Pruner) tick() {
+	floor := p.RetentionFloor(atomic.LoadUint64(&p.finalizedSlot))
+	if p.pruned >= floor {
+		return
+	}
+
+	to := floor
+	if max := p.pruned + p.cfg.MaxDeletionsPerTick; to > max {
+		to = max
+	}
+
+	filesDeleted, bytesReclaimed, err := p.db.DeleteRange(p.pruned, to)
+	if err != nil {
+		p.logger.Error(
+			"failed to prune availability store range",
+			"from", p.pruned, "to", to, "err", err,
+		)
+		return
+	}
+
+	p.metrics.IncrCounter([]string{"da", "pruner", "files_deleted"}, float64(filesDeleted))
+	p.metrics.IncrCounter([]string{"da", "pruner", "bytes_reclaimed"}, float64(bytesReclaimed))
+	p.pruned = to
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package store_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+	dastore "github.com/berachain/beacon-kit/mod/da/pkg/store"
+	"github.com/prysmaticlabs/prysm/v4/beacon-chain/core/feed"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRetentionSpec is a minimal dastore.RetentionSpec for tests.
+type fakeRetentionSpec struct {
+	minEpochs     uint64
+	slotsPerEpoch uint64
+}
+
+func (f fakeRetentionSpec) MinEpochsForBlobsSidecarsRequest() uint64 { return f.minEpochs }
+func (f fakeRetentionSpec) SlotsPerEpoch() uint64                    { return f.slotsPerEpoch }
+
+// fakeRangeDeleter records every DeleteRange call it receives, so tests can
+// assert only expired ranges were ever touched.
+type fakeRangeDeleter struct {
+	mu      sync.Mutex
+	deleted []struct{ from, to uint64 }
+}
+
+func (f *fakeRangeDeleter) DeleteRange(from, to uint64) (int, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, struct{ from, to uint64 }{from, to})
+	return int(to - from), int64(to-from) * 1024, nil
+}
+
+// snapshot returns a copy of the ranges deleted so far, safe to inspect
+// from a test goroutine while Pruner's tick loop may still be running.
+func (f *fakeRangeDeleter) snapshot() []struct{ from, to uint64 } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]struct{ from, to uint64 }, len(f.deleted))
+	copy(out, f.deleted)
+	return out
+}
+
+func TestPruner_RetentionFloor(t *testing.T) {
+	spec := fakeRetentionSpec{minEpochs: 4, slotsPerEpoch: 32}
+	p := dastore.NewPruner(&fakeRangeDeleter{}, spec, log.NewNopLogger(), dastore.PrunerConfig{})
+
+	// finalizedSlot below the retention window floors at zero.
+	require.EqualValues(t, 0, p.RetentionFloor(100))
+	// 500 - 4*32 = 372.
+	require.EqualValues(t, 372, p.RetentionFloor(500))
+}
+
+func TestPruner_RetentionFloor_Override(t *testing.T) {
+	spec := fakeRetentionSpec{minEpochs: 4, slotsPerEpoch: 32}
+	p := dastore.NewPruner(
+		&fakeRangeDeleter{}, spec, log.NewNopLogger(),
+		dastore.PrunerConfig{RetentionEpochsOverride: 1},
+	)
+
+	// 500 - 1*32 = 468, overriding the spec's 4 epochs.
+	require.EqualValues(t, 468, p.RetentionFloor(500))
+}
+
+func TestPruner_HandleNotification_DoesNotDeleteSynchronously(t *testing.T) {
+	spec := fakeRetentionSpec{minEpochs: 1, slotsPerEpoch: 10}
+	deleter := &fakeRangeDeleter{}
+	p := dastore.NewPruner(deleter, spec, log.NewNopLogger(), dastore.PrunerConfig{})
+
+	advance := func(slot uint64) {
+		p.HandleNotification(&feed.Event{Data: dastore.FinalizedSlotData{Slot: slot}})
+	}
+
+	// No finalized slot observed yet: nothing should be eligible.
+	require.EqualValues(t, 0, p.RetentionFloor(0))
+
+	// Finalize slot 50: floor is 50 - 1*10 = 40.
+	advance(50)
+	require.EqualValues(t, 40, p.RetentionFloor(50))
+
+	// A notification carrying an unrelated payload is ignored without
+	// panicking; the floor for a given finalized slot is unchanged.
+	p.HandleNotification(&feed.Event{Data: "not-a-finalized-slot"})
+	require.EqualValues(t, 40, p.RetentionFloor(50))
+
+	// Advancing further raises the floor accordingly, simulating ongoing
+	// slot advancement; ranges at or above the floor remain queryable
+	// (DeleteRange is simply never called for them).
+	advance(100)
+	require.EqualValues(t, 90, p.RetentionFloor(100))
+	require.Empty(t, deleter.deleted, "HandleNotification must not delete synchronously")
+}
+
+func TestPruner_Tick_DeletesOnlyExpiredRanges(t *testing.T) {
+	spec := fakeRetentionSpec{minEpochs: 1, slotsPerEpoch: 10}
+	deleter := &fakeRangeDeleter{}
+	p := dastore.NewPruner(
+		deleter, spec, log.NewNopLogger(),
+		dastore.PrunerConfig{PruneInterval: 10 * time.Millisecond},
+	)
+
+	p.Start()
+	defer p.Stop()
+
+	// Finalize slot 50: floor is 50 - 1*10 = 40, so [0, 40) should be
+	// pruned on the next tick, leaving slots at or above 40 queryable.
+	p.HandleNotification(&feed.Event{Data: dastore.FinalizedSlotData{Slot: 50}})
+
+	require.Eventually(t, func() bool {
+		return len(deleter.snapshot()) > 0
+	}, time.Second, 5*time.Millisecond, "tick never deleted the expired range")
+
+	deleted := deleter.snapshot()
+	require.Len(t, deleted, 1)
+	require.EqualValues(t, 0, deleted[0].from)
+	require.EqualValues(t, 40, deleted[0].to)
+
+	// A later tick with no further slot advancement must not re-delete the
+	// range it already pruned.
+	time.Sleep(50 * time.Millisecond)
+	require.Len(
+		t, deleter.snapshot(), 1,
+		"already-pruned range must not be deleted again",
+	)
+}
+
+func TestPruner_Tick_CapsDeletionsPerTick(t *testing.T) {
+	spec := fakeRetentionSpec{minEpochs: 0, slotsPerEpoch: 1}
+	deleter := &fakeRangeDeleter{}
+	p := dastore.NewPruner(
+		deleter, spec, log.NewNopLogger(),
+		dastore.PrunerConfig{
+			PruneInterval:       10 * time.Millisecond,
+			MaxDeletionsPerTick: 10,
+		},
+	)
+
+	p.Start()
+	defer p.Stop()
+
+	p.HandleNotification(&feed.Event{Data: dastore.FinalizedSlotData{Slot: 25}})
+
+	require.Eventually(t, func() bool {
+		var total uint64
+		for _, r := range deleter.snapshot() {
+			total += r.to - r.from
+		}
+		return total >= 25
+	}, time.Second, 5*time.Millisecond, "tick never finished pruning the full expired range")
+
+	deleted := deleter.snapshot()
+	require.Greater(
+		t, len(deleted), 1,
+		"a 25-slot prune capped at 10 per tick should take more than one tick",
+	)
+	for _, r := range deleted {
+		require.LessOrEqual(t, r.to-r.from, uint64(10))
+	}
+}
@@ -27,8 +27,10 @@ package components
 
 import (
 	"os"
+	"time"
 
 	"cosmossdk.io/log"
+	"github.com/berachain/beacon-kit/async/notify"
 	"github.com/berachain/beacon-kit/mod/consensus-types/pkg/types"
 	dastore "github.com/berachain/beacon-kit/mod/da/pkg/store"
 	"github.com/berachain/beacon-kit/mod/primitives"
@@ -38,26 +40,103 @@ import (
 	"github.com/spf13/cast"
 )
 
+// finalizedSlotFeedName and pruneQueueID identify the notify.Service feed
+// and GCD queue ProvideDAPruner's Pruner is wired to, matching whatever
+// component dispatches finalized-slot notifications.
+const (
+	finalizedSlotFeedName = "finalized-slot"
+	pruneQueueID          = "da-pruner"
+)
+
+// daMetricsGaugeInterval is the width of the wall-clock windows the shared
+// in-memory metrics sink buckets samples into for the availability store
+// subsystem.
+const daMetricsGaugeInterval = time.Minute
+
+// ProvideDAMetricsSink provides the notify.MetricsSink shared by the DA
+// subsystem's components, so ProvideDAPruner's files-deleted and
+// bytes-reclaimed counters land somewhere observable instead of the
+// default no-op sink.
+func ProvideDAMetricsSink() notify.MetricsSink {
+	return notify.NewInMemorySink(daMetricsGaugeInterval, 60)
+}
+
+// ProvideBlobRangeDB provides the filedb.RangeDB backing the availability
+// store. It is a dependency of both ProvideAvailibilityStore and
+// ProvideDAPruner, which the depinject container resolves to a single
+// instance and injects into both, so the live store and the pruner share
+// one handle onto data/blobs instead of opening it twice.
+func ProvideBlobRangeDB(
+	appOpts servertypes.AppOptions, logger log.Logger,
+) *filedb.RangeDB {
+	return filedb.NewRangeDB(
+		filedb.NewDB(
+			filedb.WithRootDirectory(
+				cast.ToString(
+					appOpts.Get(flags.FlagHome),
+				)+"/data/blobs",
+			),
+			filedb.WithFileExtension("ssz"),
+			filedb.WithDirectoryPermissions(os.ModePerm),
+			filedb.WithLogger(logger),
+		),
+	)
+}
+
 // ProvideAvailibilityStore provides the availability store.
 func ProvideAvailibilityStore(
-	appOpts servertypes.AppOptions,
+	rangeDB *filedb.RangeDB,
 	chainSpec primitives.ChainSpec,
 	logger log.Logger,
 ) (*dastore.Store[types.BeaconBlockBody], error) {
 	return dastore.New[types.BeaconBlockBody](
-		filedb.NewRangeDB(
-			filedb.NewDB(
-				filedb.WithRootDirectory(
-					cast.ToString(
-						appOpts.Get(flags.FlagHome),
-					)+"/data/blobs",
-				),
-				filedb.WithFileExtension("ssz"),
-				filedb.WithDirectoryPermissions(os.ModePerm),
-				filedb.WithLogger(logger),
-			),
-		),
+		rangeDB,
 		logger.With("service", "beacon-kit.da.store"),
 		chainSpec,
 	), nil
-}
\ No newline at end of file
+}
+
+// ProvideDAPruner provides the availability store's retention/pruning
+// subsystem. It runs alongside the store produced by
+// ProvideAvailibilityStore, removing blob sidecars once they fall behind
+// the chain's blob retention window.
+//
+// Retention epochs default to chainSpec.MinEpochsForBlobsSidecarsRequest
+// and may be overridden via "da.pruner.retention-epochs-override"; the
+// prune tick interval and the max deletions per tick (to smooth I/O) are
+// configurable via "da.pruner.prune-interval" and
+// "da.pruner.max-deletions-per-tick" respectively.
+func ProvideDAPruner(
+	appOpts servertypes.AppOptions,
+	rangeDB *filedb.RangeDB,
+	chainSpec primitives.ChainSpec,
+	notifier *notify.Service,
+	metrics notify.MetricsSink,
+	logger log.Logger,
+) (*dastore.Pruner, error) {
+	cfg := dastore.PrunerConfig{
+		RetentionEpochsOverride: cast.ToUint64(
+			appOpts.Get("da.pruner.retention-epochs-override"),
+		),
+		MaxDeletionsPerTick: cast.ToUint64(
+			appOpts.Get("da.pruner.max-deletions-per-tick"),
+		),
+	}
+	if interval := cast.ToDuration(appOpts.Get("da.pruner.prune-interval")); interval > 0 {
+		cfg.PruneInterval = interval
+	}
+
+	pruner := dastore.NewPruner(
+		rangeDB,
+		chainSpec,
+		logger.With("service", "beacon-kit.da.pruner"),
+		cfg,
+		dastore.WithPrunerMetricsSink(metrics),
+	)
+	if err := pruner.RegisterWithNotifier(
+		notifier, finalizedSlotFeedName, pruneQueueID,
+	); err != nil {
+		return nil, err
+	}
+	return pruner, nil
+}
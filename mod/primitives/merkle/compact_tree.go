@@ -0,0 +1,362 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package merkle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrCompactTreeFull is returned when Append is called on a CompactTree
+// that has already reached 2^depth leaves.
+var ErrCompactTreeFull = errors.New("compact tree is full")
+
+// ErrInvalidRange is returned by ConsistencyProof when the requested
+// [oldSize, newSize] range is not a valid, previously observed range of
+// tree sizes.
+var ErrInvalidRange = errors.New("invalid consistency proof range")
+
+// peakKey identifies a subtree hash CompactTree has computed: the root of
+// the 2^level leaves starting at leaf index start.
+type peakKey struct {
+	start uint64
+	level uint8
+}
+
+// compactNode is a single entry on the CompactTree's stack. It is the root
+// of a fully-filled subtree of 2^level leaves, starting at leaf index
+// start, that has not yet been merged with a right sibling of the same
+// level.
+type compactNode[RootT ~[32]byte] struct {
+	start uint64
+	level uint8
+	hash  RootT
+}
+
+// CompactTree is a compact, append-only Merkle tree. Unlike Tree, which
+// materializes every node of the tree and must be rebuilt (or rehashed
+// along the updated path) whenever a leaf changes, CompactTree only ever
+// keeps the O(log N) "rightmost" subtree roots that have not yet been
+// combined with a sibling, plus the number of leaves appended so far, to
+// serve Append and Root. This makes it well suited to append-only
+// workloads, such as the deposit contract's incremental Merkle tree, where
+// leaves are only ever added at the end. To serve ConsistencyProof it also
+// memoizes every leaf and subtree hash it has computed (see peaks), so its
+// total memory grows linearly with the number of leaves appended.
+//
+// A leaf passed to Append is expected to already be a 32-byte hash, in
+// keeping with the convention used by Tree (whose own leaves are SSZ
+// chunks rather than raw, unhashed values).
+//
+// CompactTree is not safe for concurrent use.
+type CompactTree[LeafT, RootT ~[32]byte] struct {
+	// depth is the configured depth of the tree, used to pad Root() out to
+	// a fixed-depth SSZ merkleization and to bound the number of leaves.
+	depth uint8
+
+	// size is the number of leaves appended so far.
+	size uint64
+
+	// stack holds the roots of fully-filled subtrees that have not yet been
+	// merged with a right sibling, ordered from lowest to highest level.
+	// Its length is always O(depth).
+	stack []compactNode[RootT]
+
+	// peaks records every subtree hash CompactTree has ever computed
+	// (whether still on stack or since merged into something bigger),
+	// keyed by the leaf range it covers: one entry per leaf plus one per
+	// merge. It is used to recompute the hash of any previously-complete
+	// leaf range, which
+	// ConsistencyProof uses to build an RFC 6962-style proof.
+	peaks map[peakKey]RootT
+}
+
+// NewCompactTree creates an empty CompactTree configured for the given
+// depth. depth must not exceed MaxTreeDepth.
+func NewCompactTree[LeafT, RootT ~[32]byte](
+	depth uint8,
+) (*CompactTree[LeafT, RootT], error) {
+	if depth > MaxTreeDepth {
+		return nil, ErrExceededDepth
+	}
+	return &CompactTree[LeafT, RootT]{
+		depth: depth,
+		stack: make([]compactNode[RootT], 0, depth+1),
+		peaks: make(map[peakKey]RootT),
+	}, nil
+}
+
+// Len returns the number of leaves appended so far.
+func (c *CompactTree[LeafT, RootT]) Len() uint64 {
+	return c.size
+}
+
+// Append adds a new leaf to the right-hand edge of the tree. It runs in
+// amortized O(1): it pushes the leaf as a level-0 node and then collapses
+// the stack while its top two entries share a level, hashing them together
+// into the next level up. It never re-hashes a leaf that was appended
+// previously.
+func (c *CompactTree[LeafT, RootT]) Append(leaf LeafT) error {
+	if c.depth < MaxTreeDepth && c.size >= uint64(1)<<c.depth {
+		return ErrCompactTreeFull
+	}
+
+	node := compactNode[RootT]{start: c.size, level: 0, hash: RootT(leaf)}
+	c.stack = append(c.stack, node)
+	c.recordPeak(node)
+
+	for len(c.stack) >= 2 &&
+		c.stack[len(c.stack)-1].level == c.stack[len(c.stack)-2].level {
+		right := c.stack[len(c.stack)-1]
+		left := c.stack[len(c.stack)-2]
+		c.stack = c.stack[:len(c.stack)-2]
+
+		merged := compactNode[RootT]{
+			start: left.start,
+			level: left.level + 1,
+			hash:  hashPair(left.hash, right.hash),
+		}
+		c.stack = append(c.stack, merged)
+		c.recordPeak(merged)
+	}
+
+	c.size++
+	return nil
+}
+
+// recordPeak memoizes n's hash so it can be looked up again later by
+// subtreeHash, even after n itself is merged off the stack.
+func (c *CompactTree[LeafT, RootT]) recordPeak(n compactNode[RootT]) {
+	c.peaks[peakKey{start: n.start, level: n.level}] = n.hash
+}
+
+// subtreeHash returns the previously-computed hash of the 2^level leaves
+// starting at start, if CompactTree has ever finished computing it.
+func (c *CompactTree[LeafT, RootT]) subtreeHash(
+	start uint64, level uint8,
+) (RootT, bool) {
+	h, ok := c.peaks[peakKey{start: start, level: level}]
+	return h, ok
+}
+
+// Root computes the current root of the tree by folding the stack of
+// unmerged subtree roots against the zero-hash for each missing right
+// sibling, up to the configured depth, and then mixing in the leaf count.
+// This matches the convention used by Tree.HashTreeRoot (by way of
+// MerkleProofWithMixin): the returned root is over a virtual tree of
+// 2^depth leaves, zero-padded beyond size, with the leaf count mixed in as
+// the final hashing step.
+func (c *CompactTree[LeafT, RootT]) Root() (RootT, error) {
+	byLevel := make(map[uint8]RootT, len(c.stack))
+	for _, n := range c.stack {
+		byLevel[n.level] = n.hash
+	}
+
+	// When size exactly fills the tree (size == 2^depth), the stack has
+	// collapsed to a single, fully-merged node at level == depth: that
+	// hash already is the root, and the zero-padding loop below must be
+	// skipped entirely, since size's low depth bits are all zero and
+	// would otherwise fold in zero-hashes instead of this node.
+	if full, ok := byLevel[c.depth]; ok {
+		return mixinLength(full, c.size), nil
+	}
+
+	zeroHashes := zeroHashesUpTo(c.depth)
+	node := RootT(zeroHashes[0])
+	size := c.size
+
+	for h := uint8(0); h < c.depth; h++ {
+		if size&1 == 1 {
+			node = hashPair(byLevel[h], node)
+		} else {
+			node = hashPair(node, RootT(zeroHashes[h]))
+		}
+		size >>= 1
+	}
+
+	return mixinLength(node, c.size), nil
+}
+
+// ConsistencyProof returns the RFC 6962-style consistency proof between
+// the tree's state at oldSize and at newSize: the O(log n) subtree hashes
+// a verifier needs, together with the already-known root at oldSize, to
+// confirm that the tree at oldSize is a prefix of the tree at newSize.
+func (c *CompactTree[LeafT, RootT]) ConsistencyProof(
+	oldSize, newSize uint64,
+) ([][32]byte, error) {
+	if oldSize > newSize || newSize > c.size {
+		return nil, ErrInvalidRange
+	}
+	if oldSize == 0 || oldSize == newSize {
+		return nil, nil
+	}
+
+	hashes, err := c.subProof(oldSize, 0, newSize, true)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := make([][32]byte, len(hashes))
+	for i, h := range hashes {
+		proof[i] = [32]byte(h)
+	}
+	return proof, nil
+}
+
+// subProof implements RFC 6962's SUBPROOF(m, D[start:start+n], b): the
+// list of subtree hashes needed to prove the first m leaves of the range
+// [start, start+n) are a prefix of that range. b is true only while this
+// subtree's root is implicitly already known to the verifier (true for
+// the initial, outermost call from ConsistencyProof).
+func (c *CompactTree[LeafT, RootT]) subProof(
+	m, start, n uint64, b bool,
+) ([]RootT, error) {
+	if m == n {
+		if b {
+			return nil, nil
+		}
+		h, err := c.mth(start, n)
+		if err != nil {
+			return nil, err
+		}
+		return []RootT{h}, nil
+	}
+
+	k := largestPowerOfTwoBelow(n)
+	if m <= k {
+		sub, err := c.subProof(m, start, k, b)
+		if err != nil {
+			return nil, err
+		}
+		h, err := c.mth(start+k, n-k)
+		if err != nil {
+			return nil, err
+		}
+		return append(sub, h), nil
+	}
+
+	sub, err := c.subProof(m-k, start+k, n-k, false)
+	if err != nil {
+		return nil, err
+	}
+	h, err := c.mth(start, k)
+	if err != nil {
+		return nil, err
+	}
+	return append(sub, h), nil
+}
+
+// mth returns the Merkle tree hash of the n leaves starting at start,
+// i.e. MTH(D[start:start+n]) in RFC 6962 terms. It first tries the
+// memoized hash for this exact range (available whenever it was, at some
+// point, a complete node on CompactTree's stack); failing that, it
+// recombines from the two previously-memoized halves, recursing down to
+// single leaves, which are always memoized by Append.
+func (c *CompactTree[LeafT, RootT]) mth(start, n uint64) (RootT, error) {
+	var zero RootT
+
+	if n == 1 {
+		h, ok := c.subtreeHash(start, 0)
+		if !ok {
+			return zero, ErrInvalidRange
+		}
+		return h, nil
+	}
+
+	if isPowerOfTwo(n) && start%n == 0 {
+		if h, ok := c.subtreeHash(start, log2Floor(n)); ok {
+			return h, nil
+		}
+	}
+
+	k := largestPowerOfTwoBelow(n)
+	left, err := c.mth(start, k)
+	if err != nil {
+		return zero, err
+	}
+	right, err := c.mth(start+k, n-k)
+	if err != nil {
+		return zero, err
+	}
+	return hashPair(left, right), nil
+}
+
+// largestPowerOfTwoBelow returns the largest power of two strictly less
+// than n. n must be >= 2.
+func largestPowerOfTwoBelow(n uint64) uint64 {
+	k := uint64(1)
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
+
+// isPowerOfTwo reports whether n is a power of two.
+func isPowerOfTwo(n uint64) bool {
+	return n != 0 && n&(n-1) == 0
+}
+
+// log2Floor returns floor(log2(n)) for n >= 1.
+func log2Floor(n uint64) uint8 {
+	var l uint8
+	for n > 1 {
+		n >>= 1
+		l++
+	}
+	return l
+}
+
+// hashPair returns SHA256(left || right).
+func hashPair[RootT ~[32]byte](left, right RootT) RootT {
+	var buf [64]byte
+	copy(buf[:32], left[:])
+	copy(buf[32:], right[:])
+	return RootT(sha256.Sum256(buf[:]))
+}
+
+// mixinLength mixes the leaf count into node the same way SSZ list
+// merkleization mixes in a collection's length: hash(node || len(8 bytes
+// little-endian) || 24 zero bytes).
+func mixinLength[RootT ~[32]byte](node RootT, length uint64) RootT {
+	var buf [64]byte
+	copy(buf[:32], node[:])
+	binary.LittleEndian.PutUint64(buf[32:40], length)
+	return RootT(sha256.Sum256(buf[:]))
+}
+
+// zeroHashesUpTo returns the first depth+1 zero-hashes, where
+// zeroHashes[0] is the all-zero chunk and zeroHashes[i] is the root of a
+// fully zero-filled subtree of 2^i leaves.
+func zeroHashesUpTo(depth uint8) [][32]byte {
+	hashes := make([][32]byte, depth+1)
+	for i := uint8(1); i <= depth; i++ {
+		hashes[i] = sha256.Sum256(
+			append(append([]byte{}, hashes[i-1][:]...), hashes[i-1][:]...),
+		)
+	}
+	return hashes
+}
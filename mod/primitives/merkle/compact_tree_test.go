@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package merkle_test
+
+import (
+	"crypto/sha256"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	byteslib "github.com/berachain/beacon-kit/mod/primitives/bytes"
+	"github.com/berachain/beacon-kit/mod/primitives/merkle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactTree_MatchesTreeFromLeaves(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for trial := 0; trial < 20; trial++ {
+		numLeaves := rng.Intn(64) + 1
+		leaves := make([][32]byte, numLeaves)
+		for i := range leaves {
+			rng.Read(leaves[i][:])
+		}
+
+		ct, err := merkle.NewCompactTree[[32]byte, [32]byte](treeDepth)
+		require.NoError(t, err)
+		for _, leaf := range leaves {
+			require.NoError(t, ct.Append(leaf))
+		}
+		compactRoot, err := ct.Root()
+		require.NoError(t, err)
+
+		want, err := merkle.NewTreeFromLeavesWithDepth[[32]byte, [32]byte](
+			leaves,
+			treeDepth,
+		)
+		require.NoError(t, err)
+		wantRoot, err := want.HashTreeRoot()
+		require.NoError(t, err)
+
+		require.Equal(t, wantRoot, compactRoot)
+	}
+}
+
+func TestCompactTree_AppendIsIncremental(t *testing.T) {
+	ct, err := merkle.NewCompactTree[[32]byte, [32]byte](treeDepth)
+	require.NoError(t, err)
+
+	leaves := [][32]byte{
+		byteslib.ToBytes32([]byte("A")),
+		byteslib.ToBytes32([]byte("B")),
+		byteslib.ToBytes32([]byte("C")),
+	}
+	for i, leaf := range leaves {
+		require.NoError(t, ct.Append(leaf))
+		require.Equal(t, uint64(i+1), ct.Len())
+
+		want, err := merkle.NewTreeFromLeavesWithDepth[[32]byte, [32]byte](
+			leaves[:i+1],
+			treeDepth,
+		)
+		require.NoError(t, err)
+		wantRoot, err := want.HashTreeRoot()
+		require.NoError(t, err)
+
+		gotRoot, err := ct.Root()
+		require.NoError(t, err)
+		require.Equal(t, wantRoot, gotRoot)
+	}
+}
+
+func TestCompactTree_ConsistencyProof(t *testing.T) {
+	ct, err := merkle.NewCompactTree[[32]byte, [32]byte](treeDepth)
+	require.NoError(t, err)
+
+	for i := 0; i < 8; i++ {
+		require.NoError(
+			t,
+			ct.Append(byteslib.ToBytes32([]byte(strconv.Itoa(i)))),
+		)
+	}
+
+	proof, err := ct.ConsistencyProof(4, 8)
+	require.NoError(t, err)
+	require.NotEmpty(t, proof)
+
+	_, err = ct.ConsistencyProof(0, 0)
+	require.NoError(t, err)
+
+	_, err = ct.ConsistencyProof(5, 100)
+	require.ErrorIs(t, err, merkle.ErrInvalidRange)
+}
+
+// TestCompactTree_ConsistencyProof_IsSuccinct guards against a regression
+// to the old behavior of dumping every consumed sibling in (oldSize,
+// newSize], which grows with newSize-oldSize rather than log(newSize).
+func TestCompactTree_ConsistencyProof_IsSuccinct(t *testing.T) {
+	const numLeaves = 1000
+
+	ct, err := merkle.NewCompactTree[[32]byte, [32]byte](treeDepth)
+	require.NoError(t, err)
+	for i := 0; i < numLeaves; i++ {
+		require.NoError(
+			t,
+			ct.Append(byteslib.ToBytes32([]byte(strconv.Itoa(i)))),
+		)
+	}
+
+	proof, err := ct.ConsistencyProof(1, numLeaves)
+	require.NoError(t, err)
+	require.Less(t, len(proof), 32, "proof should be O(log n), not O(n)")
+
+	proof, err = ct.ConsistencyProof(numLeaves-1, numLeaves)
+	require.NoError(t, err)
+	require.Less(t, len(proof), 32)
+}
+
+// bruteForceMTH independently computes the RFC 6962 Merkle tree hash of
+// leaves[start:start+n] by the same power-of-two split ConsistencyProof's
+// recursion relies on, but directly from the raw leaves rather than through
+// CompactTree's memoized peaks -- an independent ground truth for
+// TestCompactTree_ConsistencyProof_Verifies below.
+func bruteForceMTH(leaves [][32]byte, start, n uint64) [32]byte {
+	if n == 1 {
+		return leaves[start]
+	}
+	k := uint64(1)
+	for k*2 < n {
+		k *= 2
+	}
+	left := bruteForceMTH(leaves, start, k)
+	right := bruteForceMTH(leaves, start+k, n-k)
+	return hashPairForTest(left, right)
+}
+
+func hashPairForTest(left, right [32]byte) [32]byte {
+	var buf [64]byte
+	copy(buf[:32], left[:])
+	copy(buf[32:], right[:])
+	return sha256.Sum256(buf[:])
+}
+
+// verifyConsistency replays RFC 6962's consistency-proof verification: it
+// folds proof, the sibling hashes ConsistencyProof(m, n) produced, back
+// together using the exact same recursive split, starting from a trusted
+// root for the first m leaves, and returns what it believes the root for
+// the first n leaves should be along with whatever of proof it did not
+// consume. It is written independently of subProof/mth (it only consumes
+// ConsistencyProof's public output), so a wrong ordering or off-by-one in
+// that recursion will make this reconstruction diverge from the
+// bruteForceMTH ground truth instead of trivially agreeing with it.
+func verifyConsistency(
+	proof [][32]byte, m, n uint64, oldRoot [32]byte, b bool,
+) ([32]byte, [][32]byte) {
+	if m == n {
+		if b {
+			return oldRoot, proof
+		}
+		return proof[0], proof[1:]
+	}
+
+	k := uint64(1)
+	for k*2 < n {
+		k *= 2
+	}
+
+	if m <= k {
+		left, rest := verifyConsistency(proof, m, k, oldRoot, b)
+		right := rest[0]
+		return hashPairForTest(left, right), rest[1:]
+	}
+
+	right, rest := verifyConsistency(proof, m-k, n-k, oldRoot, false)
+	left := rest[0]
+	return hashPairForTest(left, right), rest[1:]
+}
+
+// TestCompactTree_ConsistencyProof_Verifies checks that ConsistencyProof's
+// output actually verifies: it reconstructs the root at newSize from the
+// proof plus a trusted root at oldSize, via an independent replay of the
+// RFC 6962 verification recursion, and checks the result against a
+// from-scratch recomputation of both roots. A non-empty, right-length
+// proof built from a wrong hash ordering or off-by-one in subProof/mth
+// would fail this check even though it passes
+// TestCompactTree_ConsistencyProof_IsSuccinct.
+func TestCompactTree_ConsistencyProof_Verifies(t *testing.T) {
+	const numLeaves = 37
+
+	rng := rand.New(rand.NewSource(7))
+	leaves := make([][32]byte, numLeaves)
+	for i := range leaves {
+		rng.Read(leaves[i][:])
+	}
+
+	ct, err := merkle.NewCompactTree[[32]byte, [32]byte](treeDepth)
+	require.NoError(t, err)
+	for _, leaf := range leaves {
+		require.NoError(t, ct.Append(leaf))
+	}
+
+	for _, oldSize := range []uint64{1, 5, 20, 36, numLeaves} {
+		proof, err := ct.ConsistencyProof(oldSize, numLeaves)
+		require.NoError(t, err)
+
+		oldRoot := bruteForceMTH(leaves, 0, oldSize)
+		wantNewRoot := bruteForceMTH(leaves, 0, numLeaves)
+
+		gotNewRoot, rest := verifyConsistency(
+			proof, oldSize, numLeaves, oldRoot, true,
+		)
+		require.Empty(t, rest, "proof must be fully consumed")
+		require.Equal(t, wantNewRoot, gotNewRoot)
+	}
+}
+
+func TestCompactTree_Full(t *testing.T) {
+	const depth = uint8(2)
+
+	ct, err := merkle.NewCompactTree[[32]byte, [32]byte](depth)
+	require.NoError(t, err)
+
+	leaves := make([][32]byte, 4)
+	for i := 0; i < 4; i++ {
+		leaves[i] = byteslib.ToBytes32([]byte(strconv.Itoa(i)))
+		require.NoError(t, ct.Append(leaves[i]))
+	}
+
+	// The tree is now exactly full (size == 2^depth); Root must still
+	// reflect the appended leaves rather than the all-zero padded tree.
+	gotRoot, err := ct.Root()
+	require.NoError(t, err)
+
+	want, err := merkle.NewTreeFromLeavesWithDepth[[32]byte, [32]byte](
+		leaves,
+		depth,
+	)
+	require.NoError(t, err)
+	wantRoot, err := want.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, wantRoot, gotRoot)
+
+	err = ct.Append(byteslib.ToBytes32([]byte("overflow")))
+	require.ErrorIs(t, err, merkle.ErrCompactTreeFull)
+}
+
+func BenchmarkCompactTree_Append(b *testing.B) {
+	b.StopTimer()
+	ct, err := merkle.NewCompactTree[[32]byte, [32]byte](treeDepth)
+	require.NoError(b, err)
+	someItem := byteslib.ToBytes32([]byte("hello-world"))
+
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		require.NoError(b, ct.Append(someItem))
+	}
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package filedb stores index-keyed blobs as files on disk, one directory
+// per index under a configured root directory.
+package filedb
+
+import (
+	"os"
+
+	"cosmossdk.io/log"
+)
+
+// DB holds the on-disk layout configuration shared by everything stored
+// under its root directory.
+type DB struct {
+	rootDirectory string
+	fileExtension string
+	dirPerm       os.FileMode
+	logger        log.Logger
+}
+
+// Option configures a DB constructed by NewDB.
+type Option func(*DB)
+
+// WithRootDirectory sets the directory DB stores its index subdirectories
+// under.
+func WithRootDirectory(dir string) Option {
+	return func(db *DB) { db.rootDirectory = dir }
+}
+
+// WithFileExtension sets the extension DB appends to the files it writes
+// under each index directory.
+func WithFileExtension(ext string) Option {
+	return func(db *DB) { db.fileExtension = ext }
+}
+
+// WithDirectoryPermissions sets the permissions DB creates index
+// directories with.
+func WithDirectoryPermissions(perm os.FileMode) Option {
+	return func(db *DB) { db.dirPerm = perm }
+}
+
+// WithLogger sets the logger DB reports errors to.
+func WithLogger(logger log.Logger) Option {
+	return func(db *DB) { db.logger = logger }
+}
+
+// NewDB creates a DB configured by opts.
+func NewDB(opts ...Option) *DB {
+	db := &DB{dirPerm: os.ModePerm, logger: log.NewNopLogger()}
+	for _, opt := range opts {
+		opt(db)
+	}
+	return db
+}
+
+// RangeDB stores blobs keyed by a contiguous uint64 index, one directory
+// per index under db's root directory.
+type RangeDB struct {
+	db *DB
+}
+
+// NewRangeDB creates a RangeDB backed by db.
+func NewRangeDB(db *DB) *RangeDB {
+	return &RangeDB{db: db}
+}
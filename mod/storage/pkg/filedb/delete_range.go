@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package filedb
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// DeleteRange removes every file RangeDB has stored under index keys in
+// [from, to), using the one-directory-per-index layout described on
+// RangeDB. It returns the number of files removed and the total bytes
+// reclaimed. Indices with nothing stored are skipped rather than treated
+// as an error, since callers such as dastore.Pruner cannot know in advance
+// which indices actually hold data.
+func (r *RangeDB) DeleteRange(from, to uint64) (filesDeleted int, bytesReclaimed int64, err error) {
+	for index := from; index < to; index++ {
+		n, size, rmErr := removeIndexDirectory(
+			filepath.Join(r.db.rootDirectory, strconv.FormatUint(index, 10)),
+		)
+		if rmErr != nil {
+			return filesDeleted, bytesReclaimed, rmErr
+		}
+		filesDeleted += n
+		bytesReclaimed += size
+	}
+	return filesDeleted, bytesReclaimed, nil
+}
+
+// removeIndexDirectory removes dir and everything under it, returning the
+// number of regular files it contained and their total size. A missing
+// dir is not an error.
+func removeIndexDirectory(dir string) (filesDeleted int, bytesReclaimed int64, err error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if info, statErr := entry.Info(); statErr == nil {
+			bytesReclaimed += info.Size()
+		}
+		filesDeleted++
+	}
+
+	if err = os.RemoveAll(dir); err != nil {
+		return filesDeleted, bytesReclaimed, err
+	}
+	return filesDeleted, bytesReclaimed, nil
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (c) 2024 Berachain Foundation
+//
+// Permission is hereby granted, free of charge, to any person
+// obtaining a copy of this software and associated documentation
+// files (the "Software"), to deal in the Software without
+// restriction, including without limitation the rights to use,
+// copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following
+// conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package filedb_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/berachain/beacon-kit/mod/storage/pkg/filedb"
+	"github.com/stretchr/testify/require"
+)
+
+// writeIndexFile creates root/index/name with the given contents, creating
+// the index directory as needed.
+func writeIndexFile(t *testing.T, root string, index uint64, name string, contents []byte) {
+	t.Helper()
+	dir := filepath.Join(root, strconv.FormatUint(index, 10))
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), contents, 0o644))
+}
+
+func TestRangeDB_DeleteRange(t *testing.T) {
+	root := t.TempDir()
+	r := filedb.NewRangeDB(filedb.NewDB(filedb.WithRootDirectory(root)))
+
+	// Indices 0, 1, 2, 3, 4 each hold one file; 1 and 2 will be deleted by
+	// DeleteRange(1, 3).
+	sizes := map[uint64]int{0: 4, 1: 7, 2: 3, 3: 5, 4: 9}
+	for index, size := range sizes {
+		writeIndexFile(t, root, index, "0.ssz", make([]byte, size))
+	}
+
+	filesDeleted, bytesReclaimed, err := r.DeleteRange(1, 3)
+	require.NoError(t, err)
+	require.Equal(t, 2, filesDeleted)
+	require.EqualValues(t, sizes[1]+sizes[2], bytesReclaimed)
+
+	// The targeted range is gone...
+	require.NoDirExists(t, filepath.Join(root, "1"))
+	require.NoDirExists(t, filepath.Join(root, "2"))
+	// ...and everything outside it is untouched.
+	for _, index := range []uint64{0, 3, 4} {
+		require.FileExists(t, filepath.Join(root, strconv.FormatUint(index, 10), "0.ssz"))
+	}
+}
+
+func TestRangeDB_DeleteRange_MultipleFilesPerIndex(t *testing.T) {
+	root := t.TempDir()
+	r := filedb.NewRangeDB(filedb.NewDB(filedb.WithRootDirectory(root)))
+
+	writeIndexFile(t, root, 5, "0.ssz", make([]byte, 2))
+	writeIndexFile(t, root, 5, "1.ssz", make([]byte, 3))
+
+	filesDeleted, bytesReclaimed, err := r.DeleteRange(5, 6)
+	require.NoError(t, err)
+	require.Equal(t, 2, filesDeleted)
+	require.EqualValues(t, 5, bytesReclaimed)
+	require.NoDirExists(t, filepath.Join(root, "5"))
+}
+
+func TestRangeDB_DeleteRange_MissingIndicesAreNotAnError(t *testing.T) {
+	root := t.TempDir()
+	r := filedb.NewRangeDB(filedb.NewDB(filedb.WithRootDirectory(root)))
+
+	// No files were ever written for these indices.
+	filesDeleted, bytesReclaimed, err := r.DeleteRange(100, 110)
+	require.NoError(t, err)
+	require.Zero(t, filesDeleted)
+	require.Zero(t, bytesReclaimed)
+}
+
+func TestRangeDB_DeleteRange_EmptyRangeIsNoop(t *testing.T) {
+	root := t.TempDir()
+	r := filedb.NewRangeDB(filedb.NewDB(filedb.WithRootDirectory(root)))
+	writeIndexFile(t, root, 0, "0.ssz", make([]byte, 1))
+
+	filesDeleted, bytesReclaimed, err := r.DeleteRange(0, 0)
+	require.NoError(t, err)
+	require.Zero(t, filesDeleted)
+	require.Zero(t, bytesReclaimed)
+	require.FileExists(t, filepath.Join(root, "0", "0.ssz"))
+}